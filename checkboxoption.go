@@ -0,0 +1,72 @@
+package tview
+
+// checkboxOption is a named flag registered via AddOption, shared by the
+// bitfield-backed form items MultiCheckbox and MultiSelectDropDown.
+type checkboxOption struct {
+	name string
+	mask uint32
+}
+
+// findCheckboxOption returns the option registered under the given name.
+func findCheckboxOption(options []checkboxOption, name string) (checkboxOption, bool) {
+	for _, opt := range options {
+		if opt.name == name {
+			return opt, true
+		}
+	}
+	return checkboxOption{}, false
+}
+
+// checkboxOptionChecked returns whether all bits of the named option's mask
+// are set in checked. It returns false if no option was registered under
+// that name.
+func checkboxOptionChecked(checked uint32, options []checkboxOption, name string) bool {
+	opt, ok := findCheckboxOption(options, name)
+	if !ok {
+		return false
+	}
+	return checked&opt.mask == opt.mask
+}
+
+// setCheckboxOption sets or clears all bits of the named option's mask in
+// checked, returning the updated value and whether the option was found.
+func setCheckboxOption(checked uint32, options []checkboxOption, name string, want bool) (newChecked uint32, ok bool) {
+	opt, ok := findCheckboxOption(options, name)
+	if !ok {
+		return checked, false
+	}
+	if want {
+		checked |= opt.mask
+	} else {
+		checked &^= opt.mask
+	}
+	return checked, true
+}
+
+// checkboxCheckedNames returns the names of all options whose mask is fully
+// set in checked, in registration order.
+func checkboxCheckedNames(checked uint32, options []checkboxOption) []string {
+	var names []string
+	for _, opt := range options {
+		if checked&opt.mask == opt.mask {
+			names = append(names, opt.name)
+		}
+	}
+	return names
+}
+
+// notifyCheckboxOptionsChanged invokes optionChanged once for each option
+// whose checked state differs between old and newChecked. It is a no-op if
+// optionChanged is nil.
+func notifyCheckboxOptionsChanged(old, newChecked uint32, options []checkboxOption, optionChanged func(name string, checked bool, all uint32)) {
+	if optionChanged == nil {
+		return
+	}
+	for _, opt := range options {
+		wasChecked := old&opt.mask == opt.mask
+		isChecked := newChecked&opt.mask == opt.mask
+		if wasChecked != isChecked {
+			optionChanged(opt.name, isChecked, newChecked)
+		}
+	}
+}
@@ -1,7 +1,22 @@
 package tview
 
 import (
+	"sync"
+
 	"github.com/gdamore/tcell"
+	"github.com/mattn/go-runewidth"
+)
+
+// CheckboxOrientation is used to specify the layout direction for the bits of
+// a MultiCheckbox.
+type CheckboxOrientation int
+
+const (
+	// Horizontal lays the bits out side by side on a single row.
+	Horizontal CheckboxOrientation = iota
+
+	// Vertical stacks the bits one per row.
+	Vertical
 )
 
 // Checkbox implements a simple box for boolean values which can be checked and
@@ -10,6 +25,7 @@ import (
 // See https://github.com/rivo/tview/wiki/Checkbox for an example.
 type MultiCheckbox struct {
 	*Box
+	sync.Mutex
 
 	// Whether or not this box is checked.
 	checked uint32
@@ -18,6 +34,21 @@ type MultiCheckbox struct {
 
 	focusedbit uint
 
+	// The named flags registered via AddOption, in registration order.
+	options []checkboxOption
+
+	// The per-bit captions drawn next to each checkbox cell. A bit with no
+	// corresponding entry (or an empty string) is drawn without a caption.
+	bitLabels []string
+
+	// The layout direction of the bits: Horizontal packs them into a single
+	// row, Vertical stacks them one bit per row.
+	orientation CheckboxOrientation
+
+	// The rune to show next to the focused bit, in addition to inverting
+	// its colors. A value of 0 disables the cursor.
+	cursorRune rune
+
 	// The text to be displayed before the input area.
 	label string
 
@@ -38,6 +69,11 @@ type MultiCheckbox struct {
 	// state of this checkbox.
 	changed func(checked uint32)
 
+	// An optional function which is called, for each named option whose
+	// checked state changed, when the user changes the checked state of this
+	// checkbox. Supplements (does not replace) changed.
+	optionChanged func(name string, checked bool, all uint32)
+
 	// An optional function which is called when the user indicated that they
 	// are done entering text. The key which was pressed is provided (tab,
 	// shift-tab, or escape).
@@ -55,58 +91,77 @@ func NewMultiCheckbox() *MultiCheckbox {
 		labelColor:           Styles.SecondaryTextColor,
 		fieldBackgroundColor: Styles.ContrastBackgroundColor,
 		fieldTextColor:       Styles.PrimaryTextColor,
+		cursorRune:           '_',
 	}
 }
 
 // SetChecked sets the state of the checkbox.
 func (c *MultiCheckbox) SetChecked(checked uint32) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
 	c.checked = checked
 	return c
 }
 
 // IsChecked returns whether or not the box is checked.
 func (c *MultiCheckbox) IsChecked() uint32 {
+	c.Lock()
+	defer c.Unlock()
 	return c.checked
 }
 
 // SetLabel sets the text to be displayed before the input area.
 func (c *MultiCheckbox) SetLabel(label string) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
 	c.label = label
 	return c
 }
 
 // GetLabel returns the text to be displayed before the input area.
 func (c *MultiCheckbox) GetLabel() string {
+	c.Lock()
+	defer c.Unlock()
 	return c.label
 }
 
 // SetLabelWidth sets the screen width of the label. A value of 0 will cause the
 // primitive to use the width of the label string.
 func (c *MultiCheckbox) SetLabelWidth(width int) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
 	c.labelWidth = width
 	return c
 }
 
 // SetLabelColor sets the color of the label.
 func (c *MultiCheckbox) SetLabelColor(color tcell.Color) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
 	c.labelColor = color
 	return c
 }
 
 // SetFieldBackgroundColor sets the background color of the input area.
 func (c *MultiCheckbox) SetFieldBackgroundColor(color tcell.Color) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
 	c.fieldBackgroundColor = color
 	return c
 }
 
 // SetFieldTextColor sets the text color of the input area.
 func (c *MultiCheckbox) SetFieldTextColor(color tcell.Color) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
 	c.fieldTextColor = color
 	return c
 }
 
 // SetFormAttributes sets attributes shared by all form items.
 func (c *MultiCheckbox) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
+	c.Lock()
+	defer c.Unlock()
 	c.labelWidth = labelWidth
 	c.labelColor = labelColor
 	c.backgroundColor = bgColor
@@ -117,22 +172,184 @@ func (c *MultiCheckbox) SetFormAttributes(labelWidth int, labelColor, bgColor, f
 
 // GetFieldWidth returns this primitive's field width.
 func (c *MultiCheckbox) GetFieldWidth() int {
-	return int(c.bits)
+	c.Lock()
+	defer c.Unlock()
+	if c.orientation == Vertical {
+		width := 0
+		for i := uint(0); i < c.bits; i++ {
+			if w := c.bitCellWidth(i); w > width {
+				width = w
+			}
+		}
+		return width
+	}
+
+	width := 0
+	for i := uint(0); i < c.bits; i++ {
+		if i > 0 {
+			width++ // Gap between cells.
+		}
+		width += c.bitCellWidth(i)
+	}
+	return width
 }
 
 func (c *MultiCheckbox) SetBits(bits int) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
 	c.bits = uint(bits)
 	return c
 }
 
+// SetBitLabels sets the captions drawn next to each checkbox cell, in bit
+// order (index 0 is the least significant bit). Bits beyond the end of the
+// slice are drawn without a caption.
+func (c *MultiCheckbox) SetBitLabels(labels []string) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
+	c.bitLabels = labels
+	return c
+}
+
+// SetOrientation sets the layout direction of the bits. Horizontal (the
+// default) packs all bits into a single row; Vertical stacks them one bit
+// per row.
+func (c *MultiCheckbox) SetOrientation(orientation CheckboxOrientation) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
+	c.orientation = orientation
+	return c
+}
+
+// SetCursorRune sets the rune drawn next to the focused bit to mark it as
+// the one that will be toggled on space or enter. This is in addition to the
+// default behavior of inverting the focused cell's colors, which can be hard
+// to notice on some terminals. A value of 0 disables the cursor glyph.
+func (c *MultiCheckbox) SetCursorRune(cursor rune) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
+	c.cursorRune = cursor
+	return c
+}
+
+// bitLabel returns the caption for the given bit, or an empty string if none
+// was set.
+func (c *MultiCheckbox) bitLabel(bit uint) string {
+	if int(bit) < len(c.bitLabels) {
+		return c.bitLabels[bit]
+	}
+	return ""
+}
+
+// bitCellWidth returns the screen width occupied by the given bit, including
+// its checkbox glyph, the column reserved for the cursor rune, and, if
+// present, its caption.
+func (c *MultiCheckbox) bitCellWidth(bit uint) int {
+	if label := c.bitLabel(bit); label != "" {
+		return 2 + runewidth.StringWidth(label) // Glyph + cursor column + caption.
+	}
+	return 2 // Glyph + cursor column.
+}
+
+// bitCellPosition returns the screen coordinates, relative to (x, y), of the
+// checkbox glyph for the given bit.
+func (c *MultiCheckbox) bitCellPosition(bit uint, x, y int) (cellX, cellY int) {
+	if c.orientation == Vertical {
+		return x, y + int(bit)
+	}
+
+	cellX = x
+	for i := uint(0); i < bit; i++ {
+		cellX += c.bitCellWidth(i) + 1 // Gap between cells.
+	}
+	return cellX, y
+}
+
 // SetChangedFunc sets a handler which is called when the checked state of this
 // checkbox was changed by the user. The handler function receives the new
 // state.
 func (c *MultiCheckbox) SetChangedFunc(handler func(checked uint32)) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
 	c.changed = handler
 	return c
 }
 
+// SetOptionChangedFunc sets a handler which is called, once per named option
+// whose checked state changed, when the user or SetOptionChecked changes the
+// checked state of this checkbox. The handler receives the option's name, its new checked
+// state, and the full bitfield. This supplements SetChangedFunc and lets form
+// code work in terms of option names instead of bit positions.
+func (c *MultiCheckbox) SetOptionChangedFunc(handler func(name string, checked bool, all uint32)) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
+	c.optionChanged = handler
+	return c
+}
+
+// AddOption registers a named flag backed by the given bitmask. Once
+// registered, the option's state can be read and written by name via
+// IsOptionChecked, SetOptionChecked and CheckedNames.
+func (c *MultiCheckbox) AddOption(name string, mask uint32) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
+	c.options = append(c.options, checkboxOption{name: name, mask: mask})
+	return c
+}
+
+// IsOptionChecked returns whether all bits of the named option's mask are
+// set. It returns false if no option was registered under that name.
+func (c *MultiCheckbox) IsOptionChecked(name string) bool {
+	c.Lock()
+	defer c.Unlock()
+	return checkboxOptionChecked(c.checked, c.options, name)
+}
+
+// SetOptionChecked sets or clears all bits of the named option's mask and
+// then calls the changed and option-changed handlers. It is a no-op if no
+// option was registered under that name.
+func (c *MultiCheckbox) SetOptionChecked(name string, checked bool) *MultiCheckbox {
+	c.Lock()
+	newChecked, ok := setCheckboxOption(c.checked, c.options, name, checked)
+	if !ok {
+		c.Unlock()
+		return c
+	}
+	old := c.checked
+	c.checked = newChecked
+	c.Unlock()
+	c.notifyChanged(old, newChecked)
+	return c
+}
+
+// CheckedNames returns the names of all registered options whose mask is
+// fully set, in registration order.
+func (c *MultiCheckbox) CheckedNames() []string {
+	c.Lock()
+	defer c.Unlock()
+	return checkboxCheckedNames(c.checked, c.options)
+}
+
+// notifyChanged invokes the changed and optionChanged callbacks after the
+// checked state was updated by the user or by SetOptionChecked, comparing old against newChecked to
+// determine which named options flipped. It must be called without the lock
+// held so that handlers calling back into setters don't deadlock.
+func (c *MultiCheckbox) notifyChanged(old, newChecked uint32) {
+	c.Lock()
+	changed := c.changed
+	optionChanged := c.optionChanged
+	var options []checkboxOption
+	if optionChanged != nil {
+		options = append([]checkboxOption(nil), c.options...)
+	}
+	c.Unlock()
+
+	if changed != nil {
+		changed(newChecked)
+	}
+	notifyCheckboxOptionsChanged(old, newChecked, options, optionChanged)
+}
+
 // SetDoneFunc sets a handler which is called when the user is done using the
 // checkbox. The callback function is provided with the key that was pressed,
 // which is one of the following:
@@ -141,12 +358,16 @@ func (c *MultiCheckbox) SetChangedFunc(handler func(checked uint32)) *MultiCheck
 //   - KeyTab: Move to the next field.
 //   - KeyBacktab: Move to the previous field.
 func (c *MultiCheckbox) SetDoneFunc(handler func(key tcell.Key)) *MultiCheckbox {
+	c.Lock()
+	defer c.Unlock()
 	c.done = handler
 	return c
 }
 
 // SetFinishedFunc sets a callback invoked when the user leaves this form item.
 func (c *MultiCheckbox) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
+	c.Lock()
+	defer c.Unlock()
 	c.finished = handler
 	return c
 }
@@ -155,6 +376,9 @@ func (c *MultiCheckbox) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
 func (c *MultiCheckbox) Draw(screen tcell.Screen) {
 	c.Box.Draw(screen)
 
+	c.Lock()
+	defer c.Unlock()
+
 	// Prepare
 	x, y, width, height := c.GetInnerRect()
 	rightLimit := x + width
@@ -175,19 +399,49 @@ func (c *MultiCheckbox) Draw(screen tcell.Screen) {
 		x += drawnWidth
 	}
 
-	// Draw checkboxs
+	// Draw checkboxes.
 	var i uint
 
 	for i = 0; i < c.bits; i++ {
+		cellX, cellY := c.bitCellPosition(i, x, y)
+		if cellX >= rightLimit || cellY >= y+height {
+			continue
+		}
+
+		focused := c.focus.HasFocus() && c.focusedbit == i
 		fieldStyle := tcell.StyleDefault.Background(c.fieldBackgroundColor).Foreground(c.fieldTextColor)
-		if c.focus.HasFocus() && c.focusedbit == i {
+		if focused {
 			fieldStyle = fieldStyle.Background(c.fieldTextColor).Foreground(c.fieldBackgroundColor)
 		}
 		checkedRune := 'X'
 		if (c.checked & (0x1 << i)) == 0 {
 			checkedRune = ' '
 		}
-		screen.SetContent(x+int(i), y, checkedRune, nil, fieldStyle)
+		screen.SetContent(cellX, cellY, checkedRune, nil, fieldStyle)
+
+		if focused && c.cursorRune != 0 && cellX+1 < rightLimit {
+			screen.SetContent(cellX+1, cellY, c.cursorRune, nil, tcell.StyleDefault.Foreground(c.fieldTextColor))
+		}
+
+		if label := c.bitLabel(i); label != "" {
+			Print(screen, label, cellX+2, cellY, rightLimit-cellX-2, AlignLeft, c.labelColor)
+		}
+	}
+}
+
+// finish invokes the done and finished callbacks for the given key, without
+// the lock held, so that handlers calling back into setters don't deadlock.
+func (c *MultiCheckbox) finish(key tcell.Key) {
+	c.Lock()
+	done := c.done
+	finished := c.finished
+	c.Unlock()
+
+	if done != nil {
+		done(key)
+	}
+	if finished != nil {
+		finished(key)
 	}
 }
 
@@ -200,27 +454,50 @@ func (c *MultiCheckbox) InputHandler() func(event *tcell.EventKey, setFocus func
 			if key == tcell.KeyRune && event.Rune() != ' ' {
 				break
 			}
+			c.Lock()
+			old := c.checked
 			c.checked ^= (0x1 << c.focusedbit)
-			if c.changed != nil {
-				c.changed(c.checked)
+			newChecked := c.checked
+			c.Unlock()
+			c.notifyChanged(old, newChecked)
+		case tcell.KeyUp, tcell.KeyDown:
+			c.Lock()
+			vertical := c.orientation == Vertical
+			if !vertical {
+				c.Unlock()
+				// We're done.
+				c.finish(key)
+				break
 			}
-		case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyEscape, tcell.KeyUp, tcell.KeyDown: // We're done.
-			if c.done != nil {
-				c.done(key)
+			if key == tcell.KeyUp {
+				c.focusedbit--
+			} else {
+				c.focusedbit++
 			}
-			if c.finished != nil {
-				c.finished(key)
+			if c.focusedbit >= c.bits {
+				if key == tcell.KeyUp {
+					c.focusedbit = c.bits - 1
+				} else {
+					c.focusedbit = 0
+				}
 			}
+			c.Unlock()
+		case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyEscape: // We're done.
+			c.finish(key)
 		case tcell.KeyLeft:
+			c.Lock()
 			c.focusedbit--
-			if c.focusedbit > c.bits {
+			if c.focusedbit >= c.bits {
 				c.focusedbit = c.bits - 1
 			}
+			c.Unlock()
 		case tcell.KeyRight:
+			c.Lock()
 			c.focusedbit++
-			if c.focusedbit > c.bits {
+			if c.focusedbit >= c.bits {
 				c.focusedbit = 0
 			}
+			c.Unlock()
 		}
 	})
 }
@@ -229,19 +506,40 @@ func (c *MultiCheckbox) InputHandler() func(event *tcell.EventKey, setFocus func
 func (c *MultiCheckbox) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return c.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 		x, y := event.Position()
-		_, rectY, _, _ := c.GetInnerRect()
 		if !c.InRect(x, y) {
 			return false, nil
 		}
 
 		// Process mouse event.
-		if action == MouseLeftClick && y == rectY {
-			setFocus(c)
-			c.checked ^= (0x1 << c.focusedbit)
-			if c.changed != nil {
-				c.changed(c.checked)
+		if action == MouseLeftClick {
+			rectX, rectY, _, _ := c.GetInnerRect()
+			c.Lock()
+			if c.labelWidth > 0 {
+				rectX += c.labelWidth
+			} else {
+				rectX += runewidth.StringWidth(c.label)
+			}
+
+			var old, newChecked uint32
+			var changed bool
+			for i := uint(0); i < c.bits; i++ {
+				cellX, cellY := c.bitCellPosition(i, rectX, rectY)
+				if x == cellX && y == cellY {
+					c.focusedbit = i
+					old = c.checked
+					c.checked ^= (0x1 << i)
+					newChecked = c.checked
+					changed = true
+					consumed = true
+					break
+				}
+			}
+			c.Unlock()
+
+			if changed {
+				setFocus(c)
+				c.notifyChanged(old, newChecked)
 			}
-			consumed = true
 		}
 
 		return
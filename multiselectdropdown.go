@@ -0,0 +1,432 @@
+package tview
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/mattn/go-runewidth"
+)
+
+// MultiSelectDropDown implements a multi-select form field. Collapsed, it
+// shows a single line summarizing the checked options (e.g. "Read, Write").
+// Activating it opens a popup listing every registered option with a
+// checkbox next to it, backed by the same named-option/bitfield model as
+// MultiCheckbox.
+//
+// See https://github.com/rivo/tview/wiki/DropDown for the single-select
+// counterpart this primitive mirrors.
+type MultiSelectDropDown struct {
+	*Box
+
+	// The checked state as a bitfield; each registered option covers the bits
+	// of its mask.
+	checked uint32
+
+	// The named flags registered via AddOption, in registration order.
+	options []checkboxOption
+
+	// Whether or not the popup list is currently open.
+	open bool
+
+	// The index, within options, of the currently highlighted row while the
+	// popup is open.
+	focusedOption int
+
+	// The text to be displayed before the input area.
+	label string
+
+	// The screen width of the label area. A value of 0 means use the width of
+	// the label text.
+	labelWidth int
+
+	// The label color.
+	labelColor tcell.Color
+
+	// The background color of the input area.
+	fieldBackgroundColor tcell.Color
+
+	// The text color of the input area.
+	fieldTextColor tcell.Color
+
+	// The background color of the popup list.
+	optionsBackgroundColor tcell.Color
+
+	// The text color of the popup list.
+	optionsTextColor tcell.Color
+
+	// The background color of the highlighted row in the popup list.
+	selectedBackgroundColor tcell.Color
+
+	// The text color of the highlighted row in the popup list.
+	selectedTextColor tcell.Color
+
+	// The rune shown to indicate the field can be opened, e.g. "v".
+	openSymbolRune rune
+
+	// Whether the open symbol is drawn even when the field does not have
+	// focus. By default it is only drawn while focused.
+	alwaysDrawOpenSymbol bool
+
+	// An optional function which is called when the user changes the checked
+	// state of this field.
+	changed func(checked uint32)
+
+	// An optional function which is called, for each named option whose
+	// checked state changed, when the user changes the checked state of this
+	// field. Supplements (does not replace) changed.
+	optionChanged func(name string, checked bool, all uint32)
+
+	// An optional function which is called when the user indicated that they
+	// are done using the field. The key which was pressed is provided (tab,
+	// shift-tab, or escape).
+	done func(tcell.Key)
+
+	// A callback function set by the Form class and called when the user leaves
+	// this form item.
+	finished func(tcell.Key)
+}
+
+// NewMultiSelectDropDown returns a new multi-select drop-down field.
+func NewMultiSelectDropDown() *MultiSelectDropDown {
+	return &MultiSelectDropDown{
+		Box:                     NewBox(),
+		labelColor:              Styles.SecondaryTextColor,
+		fieldBackgroundColor:    Styles.ContrastBackgroundColor,
+		fieldTextColor:          Styles.PrimaryTextColor,
+		optionsBackgroundColor:  Styles.ContrastBackgroundColor,
+		optionsTextColor:        Styles.PrimaryTextColor,
+		selectedBackgroundColor: Styles.PrimaryTextColor,
+		selectedTextColor:       Styles.ContrastBackgroundColor,
+		openSymbolRune:          'v',
+	}
+}
+
+// AddOption registers a named flag backed by the given bitmask. It is shown
+// as a row in the popup list, in registration order.
+func (d *MultiSelectDropDown) AddOption(name string, mask uint32) *MultiSelectDropDown {
+	d.options = append(d.options, checkboxOption{name: name, mask: mask})
+	return d
+}
+
+// IsOptionChecked returns whether all bits of the named option's mask are
+// set. It returns false if no option was registered under that name.
+func (d *MultiSelectDropDown) IsOptionChecked(name string) bool {
+	return checkboxOptionChecked(d.checked, d.options, name)
+}
+
+// SetOptionChecked sets or clears all bits of the named option's mask and
+// then calls the changed and option-changed handlers. It is a no-op if no
+// option was registered under that name.
+func (d *MultiSelectDropDown) SetOptionChecked(name string, checked bool) *MultiSelectDropDown {
+	newChecked, ok := setCheckboxOption(d.checked, d.options, name, checked)
+	if !ok {
+		return d
+	}
+	old := d.checked
+	d.checked = newChecked
+	d.notifyChanged(old, newChecked)
+	return d
+}
+
+// CheckedNames returns the names of all registered options whose mask is
+// fully set, in registration order.
+func (d *MultiSelectDropDown) CheckedNames() []string {
+	return checkboxCheckedNames(d.checked, d.options)
+}
+
+// notifyChanged invokes the changed and optionChanged callbacks after the
+// checked state was updated by the user or by SetOptionChecked, comparing old
+// against newChecked to determine which named options flipped.
+func (d *MultiSelectDropDown) notifyChanged(old, newChecked uint32) {
+	if d.changed != nil {
+		d.changed(newChecked)
+	}
+	notifyCheckboxOptionsChanged(old, newChecked, d.options, d.optionChanged)
+}
+
+// SetLabel sets the text to be displayed before the input area.
+func (d *MultiSelectDropDown) SetLabel(label string) *MultiSelectDropDown {
+	d.label = label
+	return d
+}
+
+// GetLabel returns the text to be displayed before the input area.
+func (d *MultiSelectDropDown) GetLabel() string {
+	return d.label
+}
+
+// SetLabelWidth sets the screen width of the label. A value of 0 will cause the
+// primitive to use the width of the label string.
+func (d *MultiSelectDropDown) SetLabelWidth(width int) *MultiSelectDropDown {
+	d.labelWidth = width
+	return d
+}
+
+// SetLabelColor sets the color of the label.
+func (d *MultiSelectDropDown) SetLabelColor(color tcell.Color) *MultiSelectDropDown {
+	d.labelColor = color
+	return d
+}
+
+// SetFieldBackgroundColor sets the background color of the input area.
+func (d *MultiSelectDropDown) SetFieldBackgroundColor(color tcell.Color) *MultiSelectDropDown {
+	d.fieldBackgroundColor = color
+	return d
+}
+
+// SetFieldTextColor sets the text color of the input area.
+func (d *MultiSelectDropDown) SetFieldTextColor(color tcell.Color) *MultiSelectDropDown {
+	d.fieldTextColor = color
+	return d
+}
+
+// SetOpenSymbolRune sets the rune drawn at the right edge of the field to
+// indicate it can be opened. The default is 'v'.
+func (d *MultiSelectDropDown) SetOpenSymbolRune(symbol rune) *MultiSelectDropDown {
+	d.openSymbolRune = symbol
+	return d
+}
+
+// SetAlwaysDrawOpenSymbol sets whether the open symbol is drawn even when
+// this primitive does not have focus. The default is false, matching the
+// original DropDown behavior of only drawing it while focused.
+func (d *MultiSelectDropDown) SetAlwaysDrawOpenSymbol(always bool) *MultiSelectDropDown {
+	d.alwaysDrawOpenSymbol = always
+	return d
+}
+
+// SetFormAttributes sets attributes shared by all form items.
+func (d *MultiSelectDropDown) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
+	d.labelWidth = labelWidth
+	d.labelColor = labelColor
+	d.backgroundColor = bgColor
+	d.fieldTextColor = fieldTextColor
+	d.fieldBackgroundColor = fieldBgColor
+	return d
+}
+
+// GetFieldWidth returns this primitive's field width.
+func (d *MultiSelectDropDown) GetFieldWidth() int {
+	width := runewidth.StringWidth(d.summary())
+	if width < 1 {
+		width = 1
+	}
+	return width + 2 // Leave room for the open symbol and its gap.
+}
+
+// SetChangedFunc sets a handler which is called when the checked state of
+// this field was changed by the user. The handler function receives the new
+// state.
+func (d *MultiSelectDropDown) SetChangedFunc(handler func(checked uint32)) *MultiSelectDropDown {
+	d.changed = handler
+	return d
+}
+
+// SetOptionChangedFunc sets a handler which is called, once per named option
+// whose checked state changed, when the user or SetOptionChecked changes the
+// checked state of this field. The handler receives the option's name, its
+// new checked state, and the full bitfield.
+func (d *MultiSelectDropDown) SetOptionChangedFunc(handler func(name string, checked bool, all uint32)) *MultiSelectDropDown {
+	d.optionChanged = handler
+	return d
+}
+
+// SetDoneFunc sets a handler which is called when the user is done using the
+// field. The callback function is provided with the key that was pressed,
+// which is one of the following:
+//
+//   - KeyEscape: Abort. While the popup is open, Escape only closes the popup
+//     and this handler is not called.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (d *MultiSelectDropDown) SetDoneFunc(handler func(key tcell.Key)) *MultiSelectDropDown {
+	d.done = handler
+	return d
+}
+
+// SetFinishedFunc sets a callback invoked when the user leaves this form item.
+func (d *MultiSelectDropDown) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
+	d.finished = handler
+	return d
+}
+
+// summary returns the collapsed-field text listing the checked option names.
+func (d *MultiSelectDropDown) summary() string {
+	return strings.Join(d.CheckedNames(), ", ")
+}
+
+// Draw draws this primitive onto the screen.
+func (d *MultiSelectDropDown) Draw(screen tcell.Screen) {
+	d.Box.Draw(screen)
+
+	// Prepare.
+	x, y, width, height := d.GetInnerRect()
+	rightLimit := x + width
+	if height < 1 || rightLimit <= x {
+		return
+	}
+
+	// Draw label.
+	if d.labelWidth > 0 {
+		labelWidth := d.labelWidth
+		if labelWidth > rightLimit-x {
+			labelWidth = rightLimit - x
+		}
+		Print(screen, d.label, x, y, labelWidth, AlignLeft, d.labelColor)
+		x += labelWidth
+	} else {
+		_, drawnWidth := Print(screen, d.label, x, y, rightLimit-x, AlignLeft, d.labelColor)
+		x += drawnWidth
+	}
+	if x >= rightLimit {
+		return
+	}
+
+	// Draw the collapsed field.
+	fieldWidth := rightLimit - x
+	drawOpenSymbol := d.alwaysDrawOpenSymbol || d.focus.HasFocus()
+	textWidth := fieldWidth
+	if drawOpenSymbol {
+		textWidth--
+	}
+	fieldStyle := tcell.StyleDefault.Background(d.fieldBackgroundColor).Foreground(d.fieldTextColor)
+	for i := 0; i < fieldWidth; i++ {
+		screen.SetContent(x+i, y, ' ', nil, fieldStyle)
+	}
+	if textWidth > 0 {
+		Print(screen, d.summary(), x, y, textWidth, AlignLeft, d.fieldTextColor)
+	}
+	if drawOpenSymbol && fieldWidth > 0 {
+		screen.SetContent(rightLimit-1, y, d.openSymbolRune, nil, fieldStyle)
+	}
+
+	// Draw the popup list of options below the field as an overlay. Like the
+	// real DropDown's list, it is sized to the number of options rather than
+	// this field's own (typically one-line) rect, and is only clamped to the
+	// actual screen bounds.
+	if d.open {
+		screenWidth, screenHeight := screen.Size()
+		popupWidth := fieldWidth
+		if x+popupWidth > screenWidth {
+			popupWidth = screenWidth - x
+		}
+		popupY := y + 1
+		for i, opt := range d.options {
+			row := popupY + i
+			if row >= screenHeight || popupWidth <= 0 {
+				break
+			}
+
+			rowBackground, rowText := d.optionsBackgroundColor, d.optionsTextColor
+			if i == d.focusedOption {
+				rowBackground, rowText = d.selectedBackgroundColor, d.selectedTextColor
+			}
+			rowStyle := tcell.StyleDefault.Background(rowBackground).Foreground(rowText)
+			for c := 0; c < popupWidth; c++ {
+				screen.SetContent(x+c, row, ' ', nil, rowStyle)
+			}
+			checkedRune := ' '
+			if d.checked&opt.mask == opt.mask {
+				checkedRune = 'X'
+			}
+			screen.SetContent(x, row, checkedRune, nil, rowStyle)
+			Print(screen, opt.name, x+2, row, popupWidth-2, AlignLeft, rowText)
+		}
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (d *MultiSelectDropDown) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return d.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if !d.open {
+			switch key := event.Key(); key {
+			case tcell.KeyEnter, tcell.KeyRune:
+				if key == tcell.KeyRune && event.Rune() != ' ' {
+					break
+				}
+				d.open = true
+				d.focusedOption = 0
+			case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyEscape:
+				if d.done != nil {
+					d.done(key)
+				}
+				if d.finished != nil {
+					d.finished(key)
+				}
+			}
+			return
+		}
+
+		// The popup is open.
+		switch key := event.Key(); key {
+		case tcell.KeyUp:
+			d.focusedOption--
+			if d.focusedOption < 0 {
+				d.focusedOption = len(d.options) - 1
+			}
+		case tcell.KeyDown:
+			d.focusedOption++
+			if d.focusedOption >= len(d.options) {
+				d.focusedOption = 0
+			}
+		case tcell.KeyRune:
+			if event.Rune() != ' ' || len(d.options) == 0 {
+				break
+			}
+			opt := d.options[d.focusedOption]
+			old := d.checked
+			d.checked ^= opt.mask
+			d.notifyChanged(old, d.checked)
+		case tcell.KeyEnter, tcell.KeyEscape:
+			d.open = false
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (d *MultiSelectDropDown) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return d.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		rectX, rectY, rectWidth, _ := d.GetInnerRect()
+
+		if action != MouseLeftClick {
+			return false, nil
+		}
+
+		if d.InRect(x, y) && y == rectY {
+			setFocus(d)
+			d.open = !d.open
+			if d.open {
+				d.focusedOption = 0
+			}
+			return true, nil
+		}
+
+		// The popup is an overlay sized to the option count, not to this
+		// field's own rect, so its rows are matched independently of
+		// rectHeight — mirroring how Draw positions them, starting after the
+		// label.
+		fieldX := rectX
+		if d.labelWidth > 0 {
+			fieldX += d.labelWidth
+		} else {
+			fieldX += runewidth.StringWidth(d.label)
+		}
+		if d.open && x >= fieldX && x < rectX+rectWidth && y > rectY && y-rectY-1 < len(d.options) {
+			index := y - rectY - 1
+			d.focusedOption = index
+			opt := d.options[index]
+			old := d.checked
+			d.checked ^= opt.mask
+			d.notifyChanged(old, d.checked)
+			return true, nil
+		}
+
+		if d.open {
+			// Clicked outside the field and popup: close it.
+			d.open = false
+		}
+
+		return false, nil
+	})
+}